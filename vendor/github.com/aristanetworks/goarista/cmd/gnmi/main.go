@@ -5,6 +5,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"flag"
@@ -12,6 +13,8 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	pb "github.com/openconfig/gnmi/proto/gnmi"
 	"google.golang.org/grpc/codes"
@@ -26,6 +29,8 @@ gnmi [options]
   capabilities
   get PATH+
   subscribe PATH+
+  dialout-publish PATH+
+  dialout-collector
   ((update|replace PATH JSON)|(delete PATH))+
 `
 
@@ -41,6 +46,72 @@ type operation struct {
 	val    string
 }
 
+// modelsFlag collects repeated -model flags into a slice.
+type modelsFlag []string
+
+func (m *modelsFlag) String() string { return strings.Join(*m, ",") }
+
+func (m *modelsFlag) Set(s string) error {
+	*m = append(*m, s)
+	return nil
+}
+
+// parseEncoding maps an -encoding flag value (e.g. "json_ietf") to its
+// pb.Encoding, returning an error for anything gNMI doesn't define.
+func parseEncoding(s string) (pb.Encoding, error) {
+	enc, ok := pb.Encoding_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("error: unknown encoding %q", s)
+	}
+	return pb.Encoding(enc), nil
+}
+
+// parseSubscriptionMode maps a -mode flag value to its
+// pb.SubscriptionList_Mode.
+func parseSubscriptionMode(s string) (pb.SubscriptionList_Mode, error) {
+	mode, ok := pb.SubscriptionList_Mode_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("error: unknown subscription mode %q", s)
+	}
+	return pb.SubscriptionList_Mode(mode), nil
+}
+
+// parseStreamMode maps a -stream-mode flag value to its
+// pb.SubscriptionMode.
+func parseStreamMode(s string) (pb.SubscriptionMode, error) {
+	mode, ok := pb.SubscriptionMode_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("error: unknown stream mode %q", s)
+	}
+	return pb.SubscriptionMode(mode), nil
+}
+
+// negotiateModels validates the requested models and encoding against
+// the server's capabilities and resolves models to the ModelData get
+// and subscribe need, issuing a Capabilities RPC only when -model or
+// -encoding was actually set. Unrelated subcommands (dialout-publish,
+// dialout-collector, set, capabilities) never need this and must not
+// pay for a Capabilities round-trip.
+func negotiateModels(ctx context.Context, client pb.GNMIClient, models []string,
+	encoding pb.Encoding, encodingSet bool) ([]*pb.ModelData, error) {
+	if len(models) == 0 && !encodingSet {
+		return nil, nil
+	}
+	resp, err := client.Capabilities(ctx, gnmi.NewCapabilitiesRequest())
+	if err != nil {
+		return nil, err
+	}
+	if err := gnmi.ValidateModels(resp, models); err != nil {
+		return nil, err
+	}
+	if encodingSet {
+		if err := gnmi.ValidateEncoding(resp, encoding); err != nil {
+			return nil, err
+		}
+	}
+	return gnmi.ModelsFromNames(resp, models), nil
+}
+
 func main() {
 	cfg := &gnmi.Config{}
 	flag.StringVar(&cfg.Addr, "addr", "", "Address of gNMI gRPC server")
@@ -50,6 +121,32 @@ func main() {
 	flag.StringVar(&cfg.Password, "password", "", "Password to authenticate with")
 	flag.StringVar(&cfg.Username, "username", "", "Username to authenticate with")
 	flag.BoolVar(&cfg.TLS, "tls", false, "Enable TLS")
+	flag.StringVar(&cfg.CollectorAddr, "collector_addr", "",
+		"Address of the dial-out collector (dialout-publish) or to listen on (dialout-collector)")
+	sampleInterval := flag.Duration("sample_interval", 10*time.Second,
+		"Interval between samples pushed by dialout-publish")
+	var models modelsFlag
+	flag.Var(&models, "model", "Model name to request/validate against the server's "+
+		"capabilities (may be repeated); used by get and subscribe")
+	encodingName := flag.String("encoding", "", "Encoding to request for get/subscribe "+
+		"(json, json_ietf, bytes, proto, ascii), validated against the server's capabilities")
+	modeName := flag.String("mode", "stream", "Subscription mode for subscribe: stream, once or poll")
+	streamModeName := flag.String("stream-mode", "target_defined",
+		"Default stream_mode for subscribe in STREAM mode: target_defined, on_change or sample")
+	sampleIntervalFlag := flag.Duration("sample-interval", 0,
+		"sample_interval for subscribe in STREAM/sample mode")
+	heartbeatInterval := flag.Duration("heartbeat-interval", 0,
+		"heartbeat_interval for subscribe in STREAM/on_change mode")
+	suppressRedundant := flag.Bool("suppress-redundant", false,
+		"Set suppress_redundant on every subscribe Subscription")
+	updatesOnly := flag.Bool("updates-only", false,
+		"Set updates_only on the subscribe SubscriptionList")
+	qos := flag.Uint("qos", 0, "QOS marking for the subscribe SubscriptionList")
+	prefix := flag.String("prefix", "", "Prefix path for the subscribe SubscriptionList")
+	origin := flag.String("origin", "", "Origin for the subscribe SubscriptionList prefix")
+	target := flag.String("target", "", "Target for the subscribe SubscriptionList prefix")
+	outputName := flag.String("output", "plain",
+		"Output format for get/subscribe/dialout-collector: plain, json, prototext or influx")
 
 	flag.Usage = func() {
 		fmt.Fprintln(os.Stderr, help)
@@ -61,6 +158,23 @@ func main() {
 	ctx := gnmi.NewContext(context.Background(), cfg)
 	client := gnmi.Dial(cfg)
 
+	var encoding pb.Encoding
+	if *encodingName != "" {
+		var err error
+		encoding, err = parseEncoding(*encodingName)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+	}
+	formatter, err := gnmi.NewFormatter(*outputName)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	getFormatter, err := gnmi.NewGetFormatter(*outputName)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
 	var setOps []*operation
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -68,13 +182,20 @@ func main() {
 			if len(setOps) != 0 {
 				exitWithError("error: 'capabilities' not allowed after 'merge|replace|delete'")
 			}
-			exitWithError("error: 'capabilities' not supported")
+			err := capabilities(ctx, client)
+			if err != nil {
+				glog.Fatal(err)
+			}
 			return
 		case "get":
 			if len(setOps) != 0 {
 				exitWithError("error: 'get' not allowed after 'merge|replace|delete'")
 			}
-			err := get(ctx, client, gnmi.SplitPaths(args[i+1:]))
+			useModels, err := negotiateModels(ctx, client, models, encoding, *encodingName != "")
+			if err != nil {
+				exitWithError(err.Error())
+			}
+			err = get(ctx, client, gnmi.SplitPaths(args[i+1:]), useModels, encoding, getFormatter)
 			if err != nil {
 				glog.Fatal(err)
 			}
@@ -83,7 +204,55 @@ func main() {
 			if len(setOps) != 0 {
 				exitWithError("error: 'subscribe' not allowed after 'merge|replace|delete'")
 			}
-			err := subscribe(ctx, client, gnmi.SplitPaths(args[i+1:]))
+			useModels, err := negotiateModels(ctx, client, models, encoding, *encodingName != "")
+			if err != nil {
+				exitWithError(err.Error())
+			}
+			mode, err := parseSubscriptionMode(*modeName)
+			if err != nil {
+				exitWithError(err.Error())
+			}
+			streamMode, err := parseStreamMode(*streamModeName)
+			if err != nil {
+				exitWithError(err.Error())
+			}
+			opts := gnmi.SubscribeOptions{
+				Mode:              mode,
+				StreamMode:        streamMode,
+				SampleInterval:    *sampleIntervalFlag,
+				HeartbeatInterval: *heartbeatInterval,
+				SuppressRedundant: *suppressRedundant,
+				UpdatesOnly:       *updatesOnly,
+				Qos:               uint32(*qos),
+				Prefix:            gnmi.SplitPath(*prefix),
+				Origin:            *origin,
+				Target:            *target,
+			}
+			err = subscribe(ctx, client, gnmi.SplitPaths(args[i+1:]), useModels, encoding, opts, formatter)
+			if err != nil {
+				glog.Fatal(err)
+			}
+			return
+		case "dialout-publish":
+			if len(setOps) != 0 {
+				exitWithError("error: 'dialout-publish' not allowed after 'merge|replace|delete'")
+			}
+			if cfg.CollectorAddr == "" {
+				exitWithError("error: 'dialout-publish' requires -collector_addr")
+			}
+			err := gnmi.Publish(ctx, cfg, gnmi.SplitPaths(args[i+1:]), *sampleInterval)
+			if err != nil {
+				glog.Fatal(err)
+			}
+			return
+		case "dialout-collector":
+			if len(setOps) != 0 {
+				exitWithError("error: 'dialout-collector' not allowed after 'merge|replace|delete'")
+			}
+			if cfg.CollectorAddr == "" {
+				exitWithError("error: 'dialout-collector' requires -collector_addr")
+			}
+			err := dialoutCollector(ctx, cfg, formatter)
 			if err != nil {
 				glog.Fatal(err)
 			}
@@ -113,27 +282,50 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
-	err := set(ctx, client, setOps)
+	err = set(ctx, client, setOps)
 	if err != nil {
 		glog.Fatal(err)
 	}
 
 }
 
-func get(ctx context.Context, client pb.GNMIClient, paths [][]string) error {
+// capabilities prints the models, encodings and gNMI version the server
+// supports.
+func capabilities(ctx context.Context, client pb.GNMIClient) error {
+	resp, err := client.Capabilities(ctx, gnmi.NewCapabilitiesRequest())
+	if err != nil {
+		return err
+	}
+	fmt.Println("Models:")
+	for _, model := range resp.SupportedModels {
+		fmt.Printf("  %s (%s) %s\n", model.Name, model.Organization, model.Version)
+	}
+	fmt.Println("Encodings:")
+	for _, enc := range resp.SupportedEncodings {
+		fmt.Printf("  %s\n", enc)
+	}
+	fmt.Printf("gNMI version: %s\n", resp.GNMIVersion)
+	return nil
+}
+
+func get(ctx context.Context, client pb.GNMIClient, paths [][]string,
+	useModels []*pb.ModelData, encoding pb.Encoding, formatter gnmi.Formatter) error {
 	req, err := gnmi.NewGetRequest(paths)
 	if err != nil {
 		return err
 	}
+	req.UseModels = useModels
+	req.Encoding = encoding
 	resp, err := client.Get(ctx, req)
 	if err != nil {
 		return err
 	}
 	for _, notif := range resp.Notification {
-		for _, update := range notif.Update {
-			fmt.Printf("%s:\n", gnmi.StrPath(update.Path))
-			fmt.Println(strVal(update))
+		out, err := formatter.Format(notif)
+		if err != nil {
+			return err
 		}
+		fmt.Println(out)
 	}
 	return nil
 }
@@ -148,49 +340,6 @@ func extractJSON(val string) []byte {
 	return jsonBytes
 }
 
-// strVal will return a string representing the value within the supplied update
-func strVal(u *pb.Update) string {
-	if u.Value != nil {
-		return string(u.Value.Value) // Backwards compatibility with pre-v0.4 gnmi
-	}
-
-	switch v := u.Val.GetValue().(type) {
-	case *pb.TypedValue_StringVal:
-		return v.StringVal
-	case *pb.TypedValue_JsonIetfVal:
-		return string(v.JsonIetfVal)
-	case *pb.TypedValue_IntVal:
-		return fmt.Sprintf("%v", v.IntVal)
-	case *pb.TypedValue_UintVal:
-		return fmt.Sprintf("%v", v.UintVal)
-	case *pb.TypedValue_BoolVal:
-		return fmt.Sprintf("%v", v.BoolVal)
-	case *pb.TypedValue_BytesVal:
-		return string(v.BytesVal)
-	case *pb.TypedValue_DecimalVal:
-		return strDecimal64(v.DecimalVal)
-	default:
-		return fmt.Sprintf("[oops - %T]", v)
-	}
-}
-
-func strDecimal64(d *pb.Decimal64) string {
-	var i, frac uint64
-	if d.Precision > 0 {
-		div := uint64(10)
-		it := d.Precision - 1
-		for it > 0 {
-			div *= 10
-			it--
-		}
-		i = d.Digits / div
-		frac = d.Digits % div
-	} else {
-		i = d.Digits
-	}
-	return fmt.Sprintf("%d.%d", i, frac)
-}
-
 func update(p *pb.Path, v []byte) *pb.Update {
 	return &pb.Update{Path: p, Val: jsonval(v)}
 }
@@ -233,21 +382,43 @@ func set(ctx context.Context, client pb.GNMIClient, setOps []*operation) error {
 	return nil
 }
 
-func subscribe(ctx context.Context, client pb.GNMIClient, paths [][]string) error {
+// dialoutCollector listens for inbound dial-out connections and prints
+// every Notification it receives, reusing subscribe's Formatter.
+func dialoutCollector(ctx context.Context, cfg *gnmi.Config, formatter gnmi.Formatter) error {
+	collector := gnmi.NewCollector(cfg, func(notif *pb.Notification) {
+		out, err := formatter.Format(notif)
+		if err != nil {
+			glog.Errorf("error formatting notification: %v", err)
+			return
+		}
+		fmt.Println(out)
+	})
+	return collector.Serve(ctx)
+}
+
+func subscribe(ctx context.Context, client pb.GNMIClient, paths [][]string,
+	useModels []*pb.ModelData, encoding pb.Encoding, opts gnmi.SubscribeOptions,
+	formatter gnmi.Formatter) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	stream, err := client.Subscribe(ctx)
 	if err != nil {
 		return err
 	}
-	req, err := gnmi.NewSubscribeRequest(paths)
+	req, err := gnmi.NewSubscribeRequestOpts(paths, opts)
 	if err != nil {
 		return err
 	}
+	req.GetSubscribe().UseModels = useModels
+	req.GetSubscribe().Encoding = encoding
 	if err := stream.Send(req); err != nil {
 		return err
 	}
 
+	if opts.Mode == pb.SubscriptionList_POLL {
+		go pollOnStdin(stream)
+	}
+
 	for {
 		response, err := stream.Recv()
 		if err != nil {
@@ -263,11 +434,27 @@ func subscribe(ctx context.Context, client pb.GNMIClient, paths [][]string) erro
 			if !resp.SyncResponse {
 				return errors.New("initial sync failed")
 			}
+			if opts.Mode == pb.SubscriptionList_ONCE {
+				return nil
+			}
 		case *pb.SubscribeResponse_Update:
-			for _, update := range resp.Update.Update {
-				fmt.Printf("%s = %s\n", gnmi.StrPath(update.Path),
-					strVal(update))
+			out, err := formatter.Format(resp.Update)
+			if err != nil {
+				return err
 			}
+			fmt.Println(out)
+		}
+	}
+}
+
+// pollOnStdin sends a Poll request on stream for every newline-delimited
+// token read from stdin, until stdin is closed.
+func pollOnStdin(stream pb.GNMI_SubscribeClient) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if err := stream.Send(gnmi.NewSubscribePollRequest()); err != nil {
+			glog.Errorf("error sending poll request: %v", err)
+			return
 		}
 	}
 }
@@ -0,0 +1,37 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package main
+
+import (
+	"testing"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestParseSubscriptionMode(t *testing.T) {
+	mode, err := parseSubscriptionMode("once")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode != pb.SubscriptionList_ONCE {
+		t.Errorf("expected ONCE, got %v", mode)
+	}
+	if _, err := parseSubscriptionMode("bogus"); err == nil {
+		t.Error("expected error for unknown subscription mode, got nil")
+	}
+}
+
+func TestParseStreamMode(t *testing.T) {
+	mode, err := parseStreamMode("on_change")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mode != pb.SubscriptionMode_ON_CHANGE {
+		t.Errorf("expected ON_CHANGE, got %v", mode)
+	}
+	if _, err := parseStreamMode("bogus"); err == nil {
+		t.Error("expected error for unknown stream mode, got nil")
+	}
+}
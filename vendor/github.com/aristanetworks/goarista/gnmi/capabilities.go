@@ -0,0 +1,68 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// NewCapabilitiesRequest builds a CapabilityRequest.
+func NewCapabilitiesRequest() *pb.CapabilityRequest {
+	return &pb.CapabilityRequest{}
+}
+
+// ValidateModels checks that every model in names is advertised by
+// resp, returning an error naming the first one that isn't.
+func ValidateModels(resp *pb.CapabilityResponse, names []string) error {
+	supported := make(map[string]bool, len(resp.SupportedModels))
+	for _, m := range resp.SupportedModels {
+		supported[m.Name] = true
+	}
+	for _, name := range names {
+		if !supported[name] {
+			return fmt.Errorf("model %q not in server capabilities (supported: %s)",
+				name, strings.Join(supportedModelNames(resp), ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateEncoding checks that enc is one of the encodings advertised
+// by resp.
+func ValidateEncoding(resp *pb.CapabilityResponse, enc pb.Encoding) error {
+	for _, e := range resp.SupportedEncodings {
+		if e == enc {
+			return nil
+		}
+	}
+	return fmt.Errorf("encoding %s not in server capabilities", enc)
+}
+
+// ModelsFromNames returns the ModelData entries in resp matching names.
+// Callers should validate names with ValidateModels first.
+func ModelsFromNames(resp *pb.CapabilityResponse, names []string) []*pb.ModelData {
+	byName := make(map[string]*pb.ModelData, len(resp.SupportedModels))
+	for _, m := range resp.SupportedModels {
+		byName[m.Name] = m
+	}
+	var models []*pb.ModelData
+	for _, name := range names {
+		if m, ok := byName[name]; ok {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+func supportedModelNames(resp *pb.CapabilityResponse) []string {
+	names := make([]string, len(resp.SupportedModels))
+	for i, m := range resp.SupportedModels {
+		names[i] = m.Name
+	}
+	return names
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"testing"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func testCapabilities() *pb.CapabilityResponse {
+	return &pb.CapabilityResponse{
+		SupportedModels: []*pb.ModelData{
+			{Name: "openconfig-interfaces", Organization: "OpenConfig", Version: "2.0.0"},
+			{Name: "openconfig-system", Organization: "OpenConfig", Version: "1.0.0"},
+		},
+		SupportedEncodings: []pb.Encoding{pb.Encoding_JSON, pb.Encoding_PROTO},
+	}
+}
+
+func TestValidateModels(t *testing.T) {
+	resp := testCapabilities()
+	if err := ValidateModels(resp, []string{"openconfig-interfaces"}); err != nil {
+		t.Errorf("unexpected error for supported model: %v", err)
+	}
+	if err := ValidateModels(resp, nil); err != nil {
+		t.Errorf("unexpected error for no models: %v", err)
+	}
+	if err := ValidateModels(resp, []string{"openconfig-bogus"}); err == nil {
+		t.Error("expected error for unsupported model, got nil")
+	}
+}
+
+func TestValidateEncoding(t *testing.T) {
+	resp := testCapabilities()
+	if err := ValidateEncoding(resp, pb.Encoding_JSON); err != nil {
+		t.Errorf("unexpected error for supported encoding: %v", err)
+	}
+	if err := ValidateEncoding(resp, pb.Encoding_ASCII); err == nil {
+		t.Error("expected error for unsupported encoding, got nil")
+	}
+}
+
+func TestModelsFromNames(t *testing.T) {
+	resp := testCapabilities()
+	models := ModelsFromNames(resp, []string{"openconfig-system", "openconfig-bogus"})
+	if len(models) != 1 {
+		t.Fatalf("expected 1 model, got %d: %v", len(models), models)
+	}
+	if models[0].Name != "openconfig-system" {
+		t.Errorf("expected openconfig-system, got %q", models[0].Name)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+// Package gnmi provides helpers to build and issue gNMI RPCs from a
+// single shared configuration.
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/aristanetworks/glog"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// Config is the gNMI client configuration, populated from command-line
+// flags in main and shared across Get/Set/Subscribe and the dial-out
+// collector/publisher.
+type Config struct {
+	Addr     string
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	Username string
+	Password string
+	TLS      bool
+
+	// CollectorAddr is the address the dial-out publisher connects to,
+	// or the address the dial-out collector listens on.
+	CollectorAddr string
+}
+
+type ctxKey struct{}
+
+// NewContext returns a new context.Context with Config embedded, along
+// with username/password metadata if configured.
+func NewContext(ctx context.Context, cfg *Config) context.Context {
+	if cfg.Username != "" {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(
+			"username", cfg.Username,
+			"password", cfg.Password))
+	}
+	return context.WithValue(ctx, ctxKey{}, cfg)
+}
+
+// ConfigFromContext extracts the Config embedded by NewContext, if any.
+func ConfigFromContext(ctx context.Context) (*Config, bool) {
+	cfg, ok := ctx.Value(ctxKey{}).(*Config)
+	return cfg, ok
+}
+
+// Dial connects to a gNMI server and returns a client.
+func Dial(cfg *Config) pb.GNMIClient {
+	opts := dialOptions(cfg)
+	conn, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	return pb.NewGNMIClient(conn)
+}
+
+// dialOptions builds the grpc.DialOptions shared by the dial-in client
+// and the dial-out publisher.
+func dialOptions(cfg *Config) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if cfg.TLS || cfg.CAFile != "" || cfg.CertFile != "" {
+		tlsConfig := &tls.Config{}
+		if cfg.CAFile != "" {
+			b, err := ioutil.ReadFile(cfg.CAFile)
+			if err != nil {
+				glog.Fatal(err)
+			}
+			cp := x509.NewCertPool()
+			if !cp.AppendCertsFromPEM(b) {
+				glog.Fatalf("credentials: failed to append certificates from %q", cfg.CAFile)
+			}
+			tlsConfig.RootCAs = cp
+		}
+		if cfg.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+			if err != nil {
+				glog.Fatal(err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	return opts
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/aristanetworks/glog"
+	"github.com/aristanetworks/goarista/gnmi/dialout"
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Bounds on the backoff used by Publish to reconnect to a collector
+// after the dial-out connection is lost.
+const (
+	dialOutMinBackoff = time.Second
+	dialOutMaxBackoff = 30 * time.Second
+)
+
+// Publish repeatedly samples the given paths from the gNMI target at
+// cfg.Addr on every interval tick and pushes each sample to the
+// collector at cfg.CollectorAddr as a SubscribeResponse over the
+// dial-out Publish RPC. It reconnects to the collector with exponential
+// backoff whenever the connection is lost. It returns an error at once
+// if a path cannot be parsed; otherwise it only returns once ctx is
+// canceled.
+func Publish(ctx context.Context, cfg *Config, paths [][]string, interval time.Duration) error {
+	client := Dial(cfg)
+	subList, err := newSubscriptionList(paths, SubscribeOptions{})
+	if err != nil {
+		return err
+	}
+
+	backoff := dialOutMinBackoff
+	for {
+		err := publishOnce(ctx, cfg, client, subList, interval)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		glog.Errorf("dial-out publish connection to %s lost: %v; reconnecting in %s",
+			cfg.CollectorAddr, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if backoff *= 2; backoff > dialOutMaxBackoff {
+			backoff = dialOutMaxBackoff
+		}
+	}
+}
+
+// publishOnce holds a single dial-out connection open, sampling and
+// publishing on every interval tick until the connection fails or ctx
+// is canceled.
+func publishOnce(ctx context.Context, cfg *Config, client pb.GNMIClient,
+	subList *pb.SubscriptionList, interval time.Duration) error {
+	conn, err := grpc.Dial(cfg.CollectorAddr, dialOptions(cfg)...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := dialout.NewGNMIDialOutClient(conn).Publish(ctx)
+	if err != nil {
+		return err
+	}
+
+	getReq := &pb.GetRequest{Path: subscriptionPaths(subList)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resp, err := client.Get(ctx, getReq)
+		if err != nil {
+			return err
+		}
+		for _, notif := range resp.Notification {
+			if err := stream.Send(&pb.SubscribeResponse{
+				Response: &pb.SubscribeResponse_Update{Update: notif},
+			}); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			stream.CloseAndRecv()
+			return ctx.Err()
+		}
+	}
+}
+
+func subscriptionPaths(subList *pb.SubscriptionList) []*pb.Path {
+	paths := make([]*pb.Path, len(subList.Subscription))
+	for i, sub := range subList.Subscription {
+		paths[i] = sub.Path
+	}
+	return paths
+}
+
+// Collector accepts dial-out connections from gNMI targets and invokes
+// a caller-supplied handler for every Notification it receives, so that
+// callers can reuse their own Get/Subscribe printing path.
+type Collector struct {
+	cfg     *Config
+	handler func(*pb.Notification)
+}
+
+// NewCollector returns a Collector listening for dial-out Publish
+// streams, calling handler for every Notification received on any of
+// them.
+func NewCollector(cfg *Config, handler func(*pb.Notification)) *Collector {
+	return &Collector{cfg: cfg, handler: handler}
+}
+
+// Serve accepts connections on cfg.CollectorAddr until ctx is canceled.
+func (c *Collector) Serve(ctx context.Context) error {
+	lis, err := net.Listen("tcp", c.cfg.CollectorAddr)
+	if err != nil {
+		return err
+	}
+	var opts []grpc.ServerOption
+	if c.cfg.TLS || c.cfg.CertFile != "" || c.cfg.CAFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.cfg.CertFile, c.cfg.KeyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+		}
+		if c.cfg.CAFile != "" {
+			b, err := ioutil.ReadFile(c.cfg.CAFile)
+			if err != nil {
+				return err
+			}
+			cp := x509.NewCertPool()
+			if !cp.AppendCertsFromPEM(b) {
+				return fmt.Errorf("credentials: failed to append certificates from %q", c.cfg.CAFile)
+			}
+			tlsConfig.ClientCAs = cp
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
+	dialout.RegisterGNMIDialOutServer(server, c)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	return server.Serve(lis)
+}
+
+// Publish implements dialout.GNMIDialOutServer. It is invoked once per
+// inbound connection and streams Notifications to c.handler until the
+// target disconnects.
+func (c *Collector) Publish(stream dialout.GNMIDialOut_PublishServer) error {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return stream.SendAndClose(&dialout.PublishResponse{})
+			}
+			return err
+		}
+		if update := resp.GetUpdate(); update != nil {
+			c.handler(update)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+// Package dialout provides hand-maintained bindings for the
+// gnmi_dialout.GNMIDialOut service described in dialout.proto. There is
+// no protoc-gen-go invocation wired up for this package yet; these
+// types and the grpc.ServiceDesc below are written by hand to match
+// what protoc-gen-go/protoc-gen-go-grpc would produce.
+package dialout
+
+import (
+	context "context"
+
+	proto "github.com/golang/protobuf/proto"
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+	grpc "google.golang.org/grpc"
+)
+
+// PublishResponse is returned by the collector when the target closes
+// its dial-out stream.
+type PublishResponse struct{}
+
+func (m *PublishResponse) Reset()         { *m = PublishResponse{} }
+func (m *PublishResponse) String() string { return proto.CompactTextString(m) }
+func (*PublishResponse) ProtoMessage()    {}
+
+// GNMIDialOutClient is the client API for GNMIDialOut service.
+type GNMIDialOutClient interface {
+	Publish(ctx context.Context, opts ...grpc.CallOption) (GNMIDialOut_PublishClient, error)
+}
+
+type gNMIDialOutClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewGNMIDialOutClient returns a client for the GNMIDialOut service on
+// the given connection.
+func NewGNMIDialOutClient(cc *grpc.ClientConn) GNMIDialOutClient {
+	return &gNMIDialOutClient{cc}
+}
+
+func (c *gNMIDialOutClient) Publish(ctx context.Context, opts ...grpc.CallOption) (GNMIDialOut_PublishClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_GNMIDialOut_serviceDesc.Streams[0], "/gnmi_dialout.GNMIDialOut/Publish", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gNMIDialOutPublishClient{stream}, nil
+}
+
+// GNMIDialOut_PublishClient is the client-side stream handle for
+// Publish: the target sends SubscribeResponses and reads back a single
+// PublishResponse on close.
+type GNMIDialOut_PublishClient interface {
+	Send(*pb.SubscribeResponse) error
+	CloseAndRecv() (*PublishResponse, error)
+	grpc.ClientStream
+}
+
+type gNMIDialOutPublishClient struct {
+	grpc.ClientStream
+}
+
+func (x *gNMIDialOutPublishClient) Send(m *pb.SubscribeResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gNMIDialOutPublishClient) CloseAndRecv() (*PublishResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PublishResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GNMIDialOutServer is the server API for GNMIDialOut service.
+type GNMIDialOutServer interface {
+	Publish(GNMIDialOut_PublishServer) error
+}
+
+// GNMIDialOut_PublishServer is the server-side stream handle for
+// Publish.
+type GNMIDialOut_PublishServer interface {
+	SendAndClose(*PublishResponse) error
+	Recv() (*pb.SubscribeResponse, error)
+	grpc.ServerStream
+}
+
+type gNMIDialOutPublishServer struct {
+	grpc.ServerStream
+}
+
+func (x *gNMIDialOutPublishServer) SendAndClose(m *PublishResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gNMIDialOutPublishServer) Recv() (*pb.SubscribeResponse, error) {
+	m := new(pb.SubscribeResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RegisterGNMIDialOutServer registers srv to handle GNMIDialOut RPCs on
+// s.
+func RegisterGNMIDialOutServer(s *grpc.Server, srv GNMIDialOutServer) {
+	s.RegisterService(&_GNMIDialOut_serviceDesc, srv)
+}
+
+func _GNMIDialOut_Publish_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GNMIDialOutServer).Publish(&gNMIDialOutPublishServer{stream})
+}
+
+var _GNMIDialOut_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gnmi_dialout.GNMIDialOut",
+	HandlerType: (*GNMIDialOutServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Publish",
+			Handler:       _GNMIDialOut_Publish_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "dialout.proto",
+}
@@ -0,0 +1,272 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Formatter renders a Notification as one or more lines of text in a
+// particular output format. Get and subscribe share the json,
+// prototext and influx Formatters; for "plain", get uses its own
+// separator (see NewGetFormatter).
+type Formatter interface {
+	Format(notif *pb.Notification) (string, error)
+}
+
+// NewFormatter returns the Formatter for the named -output format used
+// by subscribe and the dial-out collector: plain, json, prototext or
+// influx. Its "plain" formatter reproduces subscribe's original
+// "path = val" printing; use NewGetFormatter for get's "plain".
+func NewFormatter(name string) (Formatter, error) {
+	switch name {
+	case "", "plain":
+		return plainFormatter{separator: " = "}, nil
+	case "json":
+		return jsonFormatter{}, nil
+	case "prototext":
+		return prototextFormatter{}, nil
+	case "influx":
+		return influxFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("error: unknown output format %q", name)
+	}
+}
+
+// NewGetFormatter returns the Formatter for the named -output format
+// used by get. It differs from NewFormatter only for "plain", which
+// reproduces get's original two-line "path:\nval" printing; every
+// other format is shared with subscribe.
+func NewGetFormatter(name string) (Formatter, error) {
+	if name == "" || name == "plain" {
+		return plainFormatter{separator: ":\n"}, nil
+	}
+	return NewFormatter(name)
+}
+
+// plainFormatter reproduces the original ad-hoc printing, with the
+// separator between path and value differing between get (":\n") and
+// subscribe/dial-out (" = ").
+type plainFormatter struct {
+	separator string
+}
+
+func (f plainFormatter) Format(notif *pb.Notification) (string, error) {
+	var b strings.Builder
+	for i, u := range notif.Update {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s%s%s", StrPath(u.Path), f.separator, StrVal(u))
+	}
+	for i, p := range notif.Delete {
+		if i > 0 || len(notif.Update) > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s deleted", StrPath(p))
+	}
+	return b.String(), nil
+}
+
+// jsonFormatter emits one JSON object per Notification.
+type jsonFormatter struct{}
+
+type jsonUpdate struct {
+	Path string      `json:"path"`
+	Val  interface{} `json:"val"`
+}
+
+type jsonNotification struct {
+	Timestamp int64        `json:"timestamp"`
+	Prefix    string       `json:"prefix,omitempty"`
+	Updates   []jsonUpdate `json:"updates,omitempty"`
+	Deletes   []string     `json:"deletes,omitempty"`
+}
+
+func (jsonFormatter) Format(notif *pb.Notification) (string, error) {
+	out := jsonNotification{Timestamp: notif.Timestamp}
+	if notif.Prefix != nil {
+		out.Prefix = StrPath(notif.Prefix)
+	}
+	for _, u := range notif.Update {
+		out.Updates = append(out.Updates, jsonUpdate{
+			Path: StrPath(u.Path),
+			Val:  jsonVal(u),
+		})
+	}
+	for _, p := range notif.Delete {
+		out.Deletes = append(out.Deletes, StrPath(p))
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// jsonVal returns u's value as a native Go value so that json.Marshal
+// emits a JSON number/boolean/string/object instead of always the
+// quoted string StrVal renders for humans.
+func jsonVal(u *pb.Update) interface{} {
+	if u.Value != nil {
+		return string(u.Value.Value) // Backwards compatibility with pre-v0.4 gnmi
+	}
+	switch v := u.Val.GetValue().(type) {
+	case *pb.TypedValue_StringVal:
+		return v.StringVal
+	case *pb.TypedValue_JsonIetfVal:
+		return json.RawMessage(v.JsonIetfVal)
+	case *pb.TypedValue_IntVal:
+		return v.IntVal
+	case *pb.TypedValue_UintVal:
+		return v.UintVal
+	case *pb.TypedValue_BoolVal:
+		return v.BoolVal
+	case *pb.TypedValue_BytesVal:
+		return v.BytesVal
+	case *pb.TypedValue_DecimalVal:
+		return decimalFloat(v.DecimalVal)
+	default:
+		return StrVal(u)
+	}
+}
+
+// prototextFormatter emits the Notification in protobuf text format.
+type prototextFormatter struct{}
+
+func (prototextFormatter) Format(notif *pb.Notification) (string, error) {
+	return proto.MarshalTextString(notif), nil
+}
+
+// influxFormatter emits InfluxDB line protocol, one line per Update:
+// the Notification's Prefix (and any keys) become the measurement and
+// tags, and the last element of the Update's own Path becomes the
+// field; the Update's other path elements and their keys are not
+// written. If there's no
+// Prefix, the Update's Path is split instead, the same way: its
+// non-leaf elements become the measurement and tags and its leaf
+// element becomes the field.
+type influxFormatter struct{}
+
+func (influxFormatter) Format(notif *pb.Notification) (string, error) {
+	var b strings.Builder
+	for i, u := range notif.Update {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		writeInfluxLine(&b, notif, u)
+	}
+	return b.String(), nil
+}
+
+func writeInfluxLine(b *strings.Builder, notif *pb.Notification, u *pb.Update) {
+	measurement, field, tags := "gnmi", "value", map[string]string{}
+
+	measurementElems := pathElems(notif.Prefix, nil)
+	fieldElems := pathElems(nil, u.Path)
+	if len(measurementElems) == 0 && len(fieldElems) > 0 {
+		// No Prefix to supply the measurement: fall back to treating
+		// the Update's own Path the way the combined path used to be
+		// treated, splitting it into non-leaf and leaf elements.
+		measurementElems = fieldElems[:len(fieldElems)-1]
+		fieldElems = fieldElems[len(fieldElems)-1:]
+	}
+
+	if len(fieldElems) > 0 {
+		field = fieldElems[len(fieldElems)-1].Name
+	}
+	if len(measurementElems) > 0 {
+		var parts []string
+		for _, e := range measurementElems {
+			parts = append(parts, e.Name)
+			for k, v := range e.Key {
+				tags[k] = v
+			}
+		}
+		measurement = strings.Join(parts, "/")
+	}
+
+	b.WriteString(influxEscapeMeasurement(measurement))
+	for k, v := range tags {
+		fmt.Fprintf(b, ",%s=%s", influxEscape(k), influxEscape(v))
+	}
+	fmt.Fprintf(b, " %s=%s %d", influxEscape(field), influxFieldVal(u), notif.Timestamp)
+}
+
+// influxFieldVal renders u's value as an InfluxDB line-protocol field
+// value, preserving its gNMI type: quoted strings, i/u-suffixed
+// integers, bare floats and booleans, rather than always quoting
+// StrVal's string rendering.
+func influxFieldVal(u *pb.Update) string {
+	if u.Value != nil {
+		return influxQuote(string(u.Value.Value)) // Backwards compatibility with pre-v0.4 gnmi
+	}
+	switch v := u.Val.GetValue().(type) {
+	case *pb.TypedValue_StringVal:
+		return influxQuote(v.StringVal)
+	case *pb.TypedValue_JsonIetfVal:
+		return influxQuote(string(v.JsonIetfVal))
+	case *pb.TypedValue_IntVal:
+		return fmt.Sprintf("%di", v.IntVal)
+	case *pb.TypedValue_UintVal:
+		return fmt.Sprintf("%du", v.UintVal)
+	case *pb.TypedValue_BoolVal:
+		return fmt.Sprintf("%v", v.BoolVal)
+	case *pb.TypedValue_BytesVal:
+		return influxQuote(string(v.BytesVal))
+	case *pb.TypedValue_DecimalVal:
+		return fmt.Sprintf("%v", decimalFloat(v.DecimalVal))
+	default:
+		return influxQuote(StrVal(u))
+	}
+}
+
+var influxQuoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// influxQuote quotes and escapes a string line-protocol field value.
+func influxQuote(s string) string {
+	return `"` + influxQuoteReplacer.Replace(s) + `"`
+}
+
+var influxEscapeReplacer = strings.NewReplacer(`\`, `\\`, `,`, `\,`, ` `, `\ `, `=`, `\=`)
+
+// influxEscape escapes a tag key, tag value or field key for use
+// unquoted in line protocol.
+func influxEscape(s string) string {
+	return influxEscapeReplacer.Replace(s)
+}
+
+var influxEscapeMeasurementReplacer = strings.NewReplacer(`,`, `\,`, ` `, `\ `)
+
+// influxEscapeMeasurement escapes a measurement name for use unquoted
+// in line protocol: unlike tags/fields, '=' needs no escaping there.
+func influxEscapeMeasurement(s string) string {
+	return influxEscapeMeasurementReplacer.Replace(s)
+}
+
+// pathElems returns the path elements of prefix followed by path,
+// preferring the structured Elem representation and falling back to
+// the legacy Element strings.
+func pathElems(prefix, path *pb.Path) []*pb.PathElem {
+	var elems []*pb.PathElem
+	for _, p := range []*pb.Path{prefix, path} {
+		if p == nil {
+			continue
+		}
+		if len(p.Elem) != 0 {
+			elems = append(elems, p.Elem...)
+			continue
+		}
+		for _, name := range p.Element {
+			elems = append(elems, &pb.PathElem{Name: name})
+		}
+	}
+	return elems
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func testNotification() *pb.Notification {
+	return &pb.Notification{
+		Timestamp: 1234,
+		Prefix: &pb.Path{
+			Elem: []*pb.PathElem{{Name: "interfaces"}, {Name: "interface", Key: map[string]string{"name": "et1"}}},
+		},
+		Update: []*pb.Update{
+			{
+				Path: &pb.Path{Elem: []*pb.PathElem{{Name: "state"}, {Name: "counters"}, {Name: "in-octets"}}},
+				Val:  &pb.TypedValue{Value: &pb.TypedValue_UintVal{UintVal: 42}},
+			},
+		},
+	}
+}
+
+func TestPlainFormatterGet(t *testing.T) {
+	f, err := NewGetFormatter("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(testNotification())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, ":\n") {
+		t.Errorf("expected get's plain formatter to separate path and value with \":\\n\", got %q", out)
+	}
+}
+
+func TestPlainFormatterSubscribe(t *testing.T) {
+	f, err := NewFormatter("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(testNotification())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, " = ") {
+		t.Errorf("expected subscribe's plain formatter to separate path and value with \" = \", got %q", out)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	f, err := NewFormatter("json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(testNotification())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, `"val":"42"`) {
+		t.Errorf("expected uint value to be emitted as a JSON number, not a quoted string: %q", out)
+	}
+	if !strings.Contains(out, `"val":42`) {
+		t.Errorf("expected uint value 42 in output, got %q", out)
+	}
+	if !strings.Contains(out, `"prefix"`) {
+		t.Errorf("expected non-nil prefix to be included, got %q", out)
+	}
+}
+
+func TestJSONFormatterOmitsEmptyPrefix(t *testing.T) {
+	f, err := NewFormatter("json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	notif := testNotification()
+	notif.Prefix = nil
+	out, err := f.Format(notif)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, `"prefix"`) {
+		t.Errorf("expected nil prefix to be omitted, got %q", out)
+	}
+}
+
+func TestInfluxFormatter(t *testing.T) {
+	f, err := NewFormatter("influx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(testNotification())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "interfaces/interface,name=et1 ") {
+		t.Errorf("expected measurement/tags prefix, got %q", out)
+	}
+	if !strings.Contains(out, "in-octets=42u") {
+		t.Errorf("expected uint field with 'u' suffix, got %q", out)
+	}
+	if !strings.HasSuffix(out, " 1234") {
+		t.Errorf("expected trailing timestamp, got %q", out)
+	}
+}
+
+func TestInfluxFormatterEscaping(t *testing.T) {
+	notif := &pb.Notification{
+		Timestamp: 1,
+		Update: []*pb.Update{
+			{
+				Path: &pb.Path{Elem: []*pb.PathElem{{Name: "a, b=c"}}},
+				Val:  &pb.TypedValue{Value: &pb.TypedValue_StringVal{StringVal: `hello "world"`}},
+			},
+		},
+	}
+	f, err := NewFormatter("influx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(notif)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, `a\,\ b\=c=`) {
+		t.Errorf("expected field name to be escaped, got %q", out)
+	}
+	if !strings.Contains(out, `"hello \"world\""`) {
+		t.Errorf("expected string value to be quoted and escaped, got %q", out)
+	}
+}
+
+func TestPrototextFormatter(t *testing.T) {
+	f, err := NewFormatter("prototext")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := f.Format(testNotification())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "timestamp") {
+		t.Errorf("expected prototext output to contain field names, got %q", out)
+	}
+}
+
+func TestNewFormatterUnknown(t *testing.T) {
+	if _, err := NewFormatter("bogus"); err == nil {
+		t.Error("expected error for unknown output format, got nil")
+	}
+}
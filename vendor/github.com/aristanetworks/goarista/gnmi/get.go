@@ -0,0 +1,25 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// NewGetRequest builds a GetRequest for the given paths.
+func NewGetRequest(paths [][]string) (*pb.GetRequest, error) {
+	req := &pb.GetRequest{}
+	for _, path := range paths {
+		elm, err := ParseGNMIElements(path)
+		if err != nil {
+			return nil, err
+		}
+		req.Path = append(req.Path, &pb.Path{
+			Element: path, // Backwards compatibility with pre-v0.4 gnmi
+			Elem:    elm,
+		})
+	}
+	return req, nil
+}
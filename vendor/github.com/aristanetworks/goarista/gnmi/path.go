@@ -0,0 +1,105 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SplitPaths splits a list of "/"-separated paths into their string
+// elements.
+func SplitPaths(paths []string) [][]string {
+	out := make([][]string, len(paths))
+	for i, path := range paths {
+		out[i] = SplitPath(path)
+	}
+	return out
+}
+
+// SplitPath splits a "/"-separated path into a list of elements,
+// unescaping any escaped "/" within a single element.
+func SplitPath(path string) []string {
+	var parts []string
+	var buf strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch c {
+		case '\\':
+			if i+1 < len(path) {
+				i++
+				buf.WriteByte(path[i])
+			}
+		case '/':
+			parts = append(parts, buf.String())
+			buf.Reset()
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	parts = append(parts, buf.String())
+
+	out := parts[:0]
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// StrPath builds a human-readable representation of a gNMI path.
+func StrPath(path *pb.Path) string {
+	if path == nil {
+		return "/"
+	}
+	if len(path.Elem) != 0 {
+		var b strings.Builder
+		for _, elm := range path.Elem {
+			b.WriteString("/")
+			b.WriteString(elm.Name)
+			for k, v := range elm.Key {
+				fmt.Fprintf(&b, "[%s=%s]", k, v)
+			}
+		}
+		return b.String()
+	}
+	return "/" + strings.Join(path.Element, "/")
+}
+
+// ParseGNMIElements turns a list of strings into gNMI path elements,
+// parsing "key[name=value]" style keyed elements.
+func ParseGNMIElements(elms []string) ([]*pb.PathElem, error) {
+	var pes []*pb.PathElem
+	for _, elm := range elms {
+		i := strings.Index(elm, "[")
+		if i < 0 {
+			pes = append(pes, &pb.PathElem{Name: elm})
+			continue
+		}
+		pe := &pb.PathElem{Name: elm[:i], Key: map[string]string{}}
+		for i < len(elm) {
+			if elm[i] != '[' {
+				return nil, fmt.Errorf("malformed path element %q", elm)
+			}
+			end := strings.Index(elm[i:], "]")
+			if end < 0 {
+				return nil, fmt.Errorf("malformed path element %q", elm)
+			}
+			end += i
+			kv := elm[i+1 : end]
+			eq := strings.Index(kv, "=")
+			if eq < 0 {
+				return nil, fmt.Errorf("malformed path element %q", elm)
+			}
+			pe.Key[kv[:eq]] = kv[eq+1:]
+			i = end + 1
+		}
+		pes = append(pes, pe)
+	}
+	return pes, nil
+}
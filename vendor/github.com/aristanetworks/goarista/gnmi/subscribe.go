@@ -0,0 +1,95 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"time"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// SubscribeOptions holds the SubscriptionList fields exposed by the
+// subscribe subcommand, beyond the set of paths to subscribe to.
+type SubscribeOptions struct {
+	Mode              pb.SubscriptionList_Mode
+	StreamMode        pb.SubscriptionMode
+	SampleInterval    time.Duration
+	HeartbeatInterval time.Duration
+	SuppressRedundant bool
+	UpdatesOnly       bool
+	Qos               uint32
+	Prefix            []string
+	Origin            string
+	Target            string
+}
+
+// NewSubscribeRequest builds a SubscribeRequest in STREAM mode for the
+// given paths, each one subscribed with the server's default stream
+// mode.
+func NewSubscribeRequest(paths [][]string) (*pb.SubscribeRequest, error) {
+	return NewSubscribeRequestOpts(paths, SubscribeOptions{})
+}
+
+// NewSubscribeRequestOpts builds a SubscribeRequest for the given paths
+// using the SubscriptionList options in opts.
+func NewSubscribeRequestOpts(paths [][]string, opts SubscribeOptions) (*pb.SubscribeRequest, error) {
+	subList, err := newSubscriptionList(paths, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SubscribeRequest{
+		Request: &pb.SubscribeRequest_Subscribe{Subscribe: subList},
+	}, nil
+}
+
+// NewSubscribePollRequest builds a SubscribeRequest that triggers a
+// single poll of a previously established POLL-mode subscription.
+func NewSubscribePollRequest() *pb.SubscribeRequest {
+	return &pb.SubscribeRequest{
+		Request: &pb.SubscribeRequest_Poll{Poll: &pb.Poll{}},
+	}
+}
+
+func newSubscriptionList(paths [][]string, opts SubscribeOptions) (*pb.SubscriptionList, error) {
+	subs := make([]*pb.Subscription, len(paths))
+	for i, path := range paths {
+		elm, err := ParseGNMIElements(path)
+		if err != nil {
+			return nil, err
+		}
+		subs[i] = &pb.Subscription{
+			Path: &pb.Path{
+				Element: path, // Backwards compatibility with pre-v0.4 gnmi
+				Elem:    elm,
+			},
+			Mode:              opts.StreamMode,
+			SampleInterval:    uint64(opts.SampleInterval.Nanoseconds()),
+			SuppressRedundant: opts.SuppressRedundant,
+			HeartbeatInterval: uint64(opts.HeartbeatInterval.Nanoseconds()),
+		}
+	}
+
+	subList := &pb.SubscriptionList{
+		Subscription: subs,
+		Mode:         opts.Mode,
+		UpdatesOnly:  opts.UpdatesOnly,
+	}
+	if opts.Qos != 0 {
+		subList.Qos = &pb.QOSMarking{Marking: opts.Qos}
+	}
+	if len(opts.Prefix) != 0 || opts.Origin != "" || opts.Target != "" {
+		elm, err := ParseGNMIElements(opts.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		subList.Prefix = &pb.Path{
+			Element: opts.Prefix,
+			Elem:    elm,
+			Origin:  opts.Origin,
+			Target:  opts.Target,
+		}
+	}
+	return subList, nil
+}
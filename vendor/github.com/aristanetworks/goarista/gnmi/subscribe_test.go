@@ -0,0 +1,87 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestNewSubscribeRequestOpts(t *testing.T) {
+	opts := SubscribeOptions{
+		Mode:              pb.SubscriptionList_POLL,
+		StreamMode:        pb.SubscriptionMode_SAMPLE,
+		SampleInterval:    time.Second,
+		HeartbeatInterval: 10 * time.Second,
+		SuppressRedundant: true,
+		UpdatesOnly:       true,
+		Qos:               5,
+		Prefix:            []string{"Sysdb"},
+		Origin:            "openconfig",
+		Target:            "dut1",
+	}
+	req, err := NewSubscribeRequestOpts([][]string{{"interfaces"}}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subList := req.GetSubscribe()
+	if subList == nil {
+		t.Fatal("expected a Subscribe request")
+	}
+	if subList.Mode != pb.SubscriptionList_POLL {
+		t.Errorf("Mode: expected POLL, got %v", subList.Mode)
+	}
+	if !subList.UpdatesOnly {
+		t.Error("expected UpdatesOnly to be set")
+	}
+	if subList.Qos == nil || subList.Qos.Marking != 5 {
+		t.Errorf("expected Qos marking 5, got %v", subList.Qos)
+	}
+	if subList.Prefix == nil || subList.Prefix.Origin != "openconfig" || subList.Prefix.Target != "dut1" {
+		t.Errorf("expected prefix with Origin/Target set, got %v", subList.Prefix)
+	}
+	if len(subList.Subscription) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(subList.Subscription))
+	}
+	sub := subList.Subscription[0]
+	if sub.Mode != pb.SubscriptionMode_SAMPLE {
+		t.Errorf("Subscription.Mode: expected SAMPLE, got %v", sub.Mode)
+	}
+	if sub.SampleInterval != uint64(time.Second) {
+		t.Errorf("SampleInterval: expected %d, got %d", uint64(time.Second), sub.SampleInterval)
+	}
+	if sub.HeartbeatInterval != uint64(10*time.Second) {
+		t.Errorf("HeartbeatInterval: expected %d, got %d", uint64(10*time.Second), sub.HeartbeatInterval)
+	}
+	if !sub.SuppressRedundant {
+		t.Error("expected SuppressRedundant to be set")
+	}
+}
+
+func TestNewSubscribeRequestDefaults(t *testing.T) {
+	req, err := NewSubscribeRequest([][]string{{"interfaces"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subList := req.GetSubscribe()
+	if subList.Mode != pb.SubscriptionList_STREAM {
+		t.Errorf("expected default Mode STREAM, got %v", subList.Mode)
+	}
+	if subList.Qos != nil {
+		t.Errorf("expected no Qos marking, got %v", subList.Qos)
+	}
+	if subList.Prefix != nil {
+		t.Errorf("expected no Prefix, got %v", subList.Prefix)
+	}
+}
+
+func TestNewSubscribePollRequest(t *testing.T) {
+	req := NewSubscribePollRequest()
+	if req.GetPoll() == nil {
+		t.Error("expected a Poll request")
+	}
+}
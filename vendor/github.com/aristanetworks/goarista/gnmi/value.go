@@ -0,0 +1,64 @@
+// Copyright (C) 2017  Arista Networks, Inc.
+// Use of this source code is governed by the Apache License 2.0
+// that can be found in the COPYING file.
+
+package gnmi
+
+import (
+	"fmt"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// StrVal returns a string representation of the value carried by u,
+// regardless of which TypedValue oneof field it's stored in.
+func StrVal(u *pb.Update) string {
+	if u.Value != nil {
+		return string(u.Value.Value) // Backwards compatibility with pre-v0.4 gnmi
+	}
+
+	switch v := u.Val.GetValue().(type) {
+	case *pb.TypedValue_StringVal:
+		return v.StringVal
+	case *pb.TypedValue_JsonIetfVal:
+		return string(v.JsonIetfVal)
+	case *pb.TypedValue_IntVal:
+		return fmt.Sprintf("%v", v.IntVal)
+	case *pb.TypedValue_UintVal:
+		return fmt.Sprintf("%v", v.UintVal)
+	case *pb.TypedValue_BoolVal:
+		return fmt.Sprintf("%v", v.BoolVal)
+	case *pb.TypedValue_BytesVal:
+		return string(v.BytesVal)
+	case *pb.TypedValue_DecimalVal:
+		return strDecimal64(v.DecimalVal)
+	default:
+		return fmt.Sprintf("[oops - %T]", v)
+	}
+}
+
+// decimalFloat converts a Decimal64 to its float64 value.
+func decimalFloat(d *pb.Decimal64) float64 {
+	f := float64(d.Digits)
+	for i := uint32(0); i < d.Precision; i++ {
+		f /= 10
+	}
+	return f
+}
+
+func strDecimal64(d *pb.Decimal64) string {
+	var i, frac uint64
+	if d.Precision > 0 {
+		div := uint64(10)
+		it := d.Precision - 1
+		for it > 0 {
+			div *= 10
+			it--
+		}
+		i = d.Digits / div
+		frac = d.Digits % div
+	} else {
+		i = d.Digits
+	}
+	return fmt.Sprintf("%d.%d", i, frac)
+}